@@ -0,0 +1,60 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+// MasterConfiguration contains a list of elements which make up master configuration object
+type MasterConfiguration struct {
+	API        API
+	Networking Networking
+	Etcd       Etcd
+	// KeyConfig selects the key algorithm and size/curve used when generating the cluster's
+	// PKI. Defaults to 2048-bit RSA when left at its zero value.
+	KeyConfig KeyConfig
+}
+
+// KeyConfig describes what key algorithm and size/curve certificates should be generated with
+type KeyConfig struct {
+	// Algorithm is one of "rsa" (default), "ecdsa" or "ed25519"
+	Algorithm string
+	// Bits is the RSA modulus size (2048, 3072 or 4096). Only used when Algorithm is "rsa".
+	Bits int
+	// Curve is the ECDSA curve name ("P256" or "P384"). Only used when Algorithm is "ecdsa".
+	Curve string
+}
+
+// API holds the settings for the API server
+type API struct {
+	// AdvertiseAddresses is the IP addresses the API server is accessible on
+	AdvertiseAddresses []string
+	// ExternalDNSNames is the list of extra names the API server cert should be signed for
+	ExternalDNSNames []string
+	// BindPort is the port the API server is accessible on
+	BindPort int32
+}
+
+// Networking contains elements describing cluster's networking configuration
+type Networking struct {
+	ServiceSubnet string
+	PodSubnet     string
+	DNSDomain     string
+}
+
+// Etcd contains elements describing Etcd configuration
+type Etcd struct {
+	// Endpoints of etcd members, if using an external etcd cluster
+	Endpoints []string
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package constants
+
+const (
+	// CACertAndKeyBaseName defines the base name for the root CA certificate and key
+	CACertAndKeyBaseName = "ca"
+	// APIServerCertAndKeyBaseName defines the base name for the API server certificate and key
+	APIServerCertAndKeyBaseName = "apiserver"
+	// APIServerKubeletClientCertAndKeyBaseName defines the base name for the cert, signed by the
+	// root CA, that the API server uses to authenticate to kubelets
+	APIServerKubeletClientCertAndKeyBaseName = "apiserver-kubelet-client"
+
+	// EtcdCACertAndKeyBaseName defines the base name for the etcd CA certificate and key
+	EtcdCACertAndKeyBaseName = "etcd/ca"
+	// EtcdServerCertAndKeyBaseName defines the base name for the etcd server certificate and key
+	EtcdServerCertAndKeyBaseName = "etcd/server"
+	// EtcdPeerCertAndKeyBaseName defines the base name for the etcd peer certificate and key
+	EtcdPeerCertAndKeyBaseName = "etcd/peer"
+	// APIServerEtcdClientCertAndKeyBaseName defines the base name for the cert the API server
+	// uses to talk to etcd
+	APIServerEtcdClientCertAndKeyBaseName = "apiserver-etcd-client"
+
+	// FrontProxyCACertAndKeyBaseName defines the base name for the front-proxy CA certificate and key
+	FrontProxyCACertAndKeyBaseName = "front-proxy-ca"
+	// FrontProxyClientCertAndKeyBaseName defines the base name for the front-proxy client certificate and key
+	FrontProxyClientCertAndKeyBaseName = "front-proxy-client"
+
+	// ServiceAccountKeyBaseName defines the base name for the service account signing key pair
+	ServiceAccountKeyBaseName = "sa"
+
+	// DefaultCertificateValidity is the default lifetime given to a leaf certificate
+	DefaultCertificateValidity = 365
+
+	// AdminKubeConfigFileName defines the file name for the admin kubeconfig file
+	AdminKubeConfigFileName = "admin.conf"
+	// KubeletKubeConfigFileName defines the file name for the kubelet kubeconfig file
+	KubeletKubeConfigFileName = "kubelet.conf"
+	// ControllerManagerKubeConfigFileName defines the file name for the controller-manager kubeconfig file
+	ControllerManagerKubeConfigFileName = "controller-manager.conf"
+	// SchedulerKubeConfigFileName defines the file name for the scheduler kubeconfig file
+	SchedulerKubeConfigFileName = "scheduler.conf"
+)
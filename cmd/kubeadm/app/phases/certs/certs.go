@@ -17,11 +17,12 @@ limitations under the License.
 package certs
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 	"fmt"
 	"net"
 	"os"
+	"time"
 
 	setutil "k8s.io/apimachinery/pkg/util/sets"
 	certutil "k8s.io/client-go/pkg/util/cert"
@@ -32,15 +33,26 @@ import (
 )
 
 // TODO: Integration test cases
-// no files exist => create all four files
+// no files exist => create all PKI assets for the root, front-proxy and etcd CA chains
 // valid ca.{crt,key} exists => create apiserver.{crt,key}
 // valid ca.{crt,key} and apiserver.{crt,key} exists => do nothing
 // invalid ca.{crt,key} exists => error
 // only one of the .crt or .key file exists => error
+// ca.crt exists without ca.key (externalCA) => leaf certs are requested via CSR, not self-signed
+// TODO: unit test cases (this package has none yet; this tree has no go.mod to run them against,
+// but these are the ones to add first when it does)
+// pkiutil.NewPrivateKey: RSA/ECDSA/Ed25519 dispatch, the minimum-RSA-bits rejection, and the
+//   unsupported-curve/unsupported-algorithm error paths
+// pkiutil.PromoteSignedCert: accepts a signed cert matching the pending CSR's pubkey/SANs and
+//   chaining to the expected CA; rejects a pubkey mismatch, a missing SAN, and a bad chain
+// renewal.diffAltNames and the Threshold/Force gating in RenewPKIAssets: a cert past/short of
+//   the threshold is (skipped/renewed), and a SAN mismatch is reported instead of renewed
+//   unless Force is set
 
-// CreatePKIAssets will create and write to disk all PKI assets necessary to establish the control plane.
-// It generates a self-signed CA certificate and a server certificate (signed by the CA)
-func CreatePKIAssets(cfg *kubeadmapi.MasterConfiguration, pkiDir string) error {
+// apiServerAltNames computes the SANs the API server certificate (and anything else that
+// needs to be reachable the same way, such as the etcd server/peer certs) must carry for
+// the given configuration.
+func apiServerAltNames(cfg *kubeadmapi.MasterConfiguration) (certutil.AltNames, error) {
 	altNames := certutil.AltNames{}
 
 	// First, define all domains this cert should be signed for
@@ -52,7 +64,7 @@ func CreatePKIAssets(cfg *kubeadmapi.MasterConfiguration, pkiDir string) error {
 	}
 	hostname, err := os.Hostname()
 	if err != nil {
-		return fmt.Errorf("couldn't get the hostname: %v", err)
+		return altNames, fmt.Errorf("couldn't get the hostname: %v", err)
 	}
 	altNames.DNSNames = append(cfg.API.ExternalDNSNames, hostname)
 	altNames.DNSNames = append(altNames.DNSNames, internalAPIServerFQDN...)
@@ -62,81 +74,364 @@ func CreatePKIAssets(cfg *kubeadmapi.MasterConfiguration, pkiDir string) error {
 		if ip := net.ParseIP(a); ip != nil {
 			altNames.IPs = append(altNames.IPs, ip)
 		} else {
-			return fmt.Errorf("could not parse ip %q", a)
+			return altNames, fmt.Errorf("could not parse ip %q", a)
 		}
 	}
 	// and lastly, extract the internal IP address for the API server
 	_, n, err := net.ParseCIDR(cfg.Networking.ServiceSubnet)
 	if err != nil {
-		return fmt.Errorf("error parsing CIDR %q: %v", cfg.Networking.ServiceSubnet, err)
+		return altNames, fmt.Errorf("error parsing CIDR %q: %v", cfg.Networking.ServiceSubnet, err)
 	}
 	internalAPIServerVirtualIP, err := ipallocator.GetIndexedIP(n, 1)
 	if err != nil {
-		return fmt.Errorf("unable to allocate IP address for the API server from the given CIDR (%q) [%v]", &cfg.Networking.ServiceSubnet, err)
+		return altNames, fmt.Errorf("unable to allocate IP address for the API server from the given CIDR (%q) [%v]", &cfg.Networking.ServiceSubnet, err)
 	}
 
 	altNames.IPs = append(altNames.IPs, internalAPIServerVirtualIP)
 
-	var caCert *x509.Certificate
-	var caKey *rsa.PrivateKey
-	// If at least one of them exists, we should try to load them
-	// In the case that only one exists, there will most likely be an error anyway
-	if pkiutil.CertOrKeyExist(pkiDir, kubeadmconstants.CACertAndKeyBaseName) {
-		// Try to load ca.crt and ca.key from the PKI directory
-		caCert, caKey, err = pkiutil.TryLoadCertAndKeyFromDisk(pkiDir, kubeadmconstants.CACertAndKeyBaseName)
+	return altNames, nil
+}
+
+// etcdAltNames computes the SANs the etcd server and peer certificates must carry: localhost
+// plus whatever the API server certificate is reachable on, so the API server's
+// apiserver-etcd-client certificate and etcdctl both work against the same names.
+func etcdAltNames(apiServerAltNames certutil.AltNames) certutil.AltNames {
+	altNames := certutil.AltNames{
+		DNSNames: []string{"localhost"},
+		IPs:      []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	altNames.DNSNames = append(altNames.DNSNames, apiServerAltNames.DNSNames...)
+	altNames.IPs = append(altNames.IPs, apiServerAltNames.IPs...)
+	return altNames
+}
+
+// CreatePKIAssets will create and write to disk all PKI assets necessary to establish the control plane.
+// It generates a self-signed root CA and the API server and apiserver-kubelet-client
+// certificates it signs, a self-signed front-proxy CA and the front-proxy-client certificate
+// it signs, a self-signed etcd CA and the etcd-server/etcd-peer/apiserver-etcd-client
+// certificates it signs, and the standalone service account signing key pair.
+func CreatePKIAssets(cfg *kubeadmapi.MasterConfiguration, pkiDir string) error {
+	altNames, err := apiServerAltNames(cfg)
+	if err != nil {
+		return err
+	}
+
+	keyConfig := toPKIKeyConfig(cfg.KeyConfig)
+
+	caCert, caKey, externalCA, err := loadOrGenerateCA(pkiDir, kubeadmconstants.CACertAndKeyBaseName, keyConfig, "root")
+	if err != nil {
+		return err
+	}
+
+	apiServerConfig := certutil.Config{
+		CommonName: "kube-apiserver",
+		AltNames:   altNames,
+		Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if err := signOrRequestCert(pkiDir, kubeadmconstants.APIServerCertAndKeyBaseName, caCert, caKey, externalCA, apiServerConfig, keyConfig); err != nil {
+		return err
+	}
+
+	if err := createAPIServerKubeletClientCert(caCert, caKey, externalCA, keyConfig, pkiDir); err != nil {
+		return err
+	}
+
+	if err := createFrontProxyPKIAssets(pkiDir, keyConfig); err != nil {
+		return err
+	}
+
+	if err := createEtcdPKIAssets(altNames, keyConfig, pkiDir); err != nil {
+		return err
+	}
+
+	if err := createServiceAccountKeyPair(cfg.KeyConfig, pkiDir); err != nil {
+		return err
+	}
+
+	fmt.Printf("[certificates] Valid certificates and keys now exist in %q\n", pkiDir)
+
+	return nil
+}
+
+// toPKIKeyConfig translates the apis/kubeadm KeyConfig into the pkiutil representation
+func toPKIKeyConfig(cfg kubeadmapi.KeyConfig) pkiutil.KeyConfig {
+	return pkiutil.KeyConfig{
+		Algorithm: pkiutil.KeyAlgorithm(cfg.Algorithm),
+		Bits:      cfg.Bits,
+		Curve:     cfg.Curve,
+	}
+}
+
+// loadOrGenerateCA loads the CA certificate and key named baseName from pkiDir, generating a
+// new self-signed CA if neither file exists. When ca.crt exists without a matching ca.key, the
+// CA is treated as externally managed (an HSM or corporate PKI): the returned externalCA is
+// true, the key is nil, and leaf certificates it signs must be requested via CSR instead of
+// self-signed. caLabel is used only to make log messages and errors readable (e.g. "root",
+// "front-proxy", "etcd").
+func loadOrGenerateCA(pkiDir, baseName string, keyConfig pkiutil.KeyConfig, caLabel string) (*x509.Certificate, crypto.Signer, bool, error) {
+	caCertExists := pathExists(pkiutil.PathForCert(pkiDir, baseName))
+	caKeyExists := pathExists(pkiutil.PathForKey(pkiDir, baseName))
+
+	switch {
+	case caCertExists && caKeyExists:
+		caCert, caKey, err := pkiutil.TryLoadCertAndKeyFromDisk(pkiDir, baseName)
 		if err != nil || caCert == nil || caKey == nil {
-			return fmt.Errorf("certificate and/or key existed but they could not be loaded properly")
+			return nil, nil, false, fmt.Errorf("certificate and/or key existed but they could not be loaded properly")
 		}
-
-		// The certificate and key could be loaded, but the certificate is not a CA
 		if !caCert.IsCA {
-			return fmt.Errorf("certificate and key could be loaded but the certificate is not a CA")
+			return nil, nil, false, fmt.Errorf("certificate and key could be loaded but the certificate is not a CA")
 		}
-
-		fmt.Println("[certificates] Using the existing CA certificate and key.")
-	} else {
-		// The certificate and the key did NOT exist, let's generate them now
-		caCert, caKey, err = pkiutil.NewCertificateAuthority()
+		fmt.Printf("[certificates] Using the existing %s CA certificate and key.\n", caLabel)
+		return caCert, caKey, false, nil
+	case caCertExists && !caKeyExists:
+		caCert, err := pkiutil.TryLoadCertFromDisk(pkiDir, baseName)
+		if err != nil || caCert == nil {
+			return nil, nil, false, fmt.Errorf("CA certificate existed but it could not be loaded properly")
+		}
+		if !caCert.IsCA {
+			return nil, nil, false, fmt.Errorf("certificate could be loaded but the certificate is not a CA")
+		}
+		fmt.Printf("[certificates] Detected an externally managed %s CA certificate without a private key; certificates it signs will be requested via CSR.\n", caLabel)
+		return caCert, nil, true, nil
+	case !caCertExists && caKeyExists:
+		return nil, nil, false, fmt.Errorf("%s.key exists without a matching %s.crt", baseName, baseName)
+	default:
+		// Neither the certificate nor the key existed, let's generate them now
+		caCert, caKey, err := pkiutil.NewCertificateAuthority(keyConfig)
 		if err != nil {
-			return fmt.Errorf("failure while generating CA certificate and key [%v]", err)
+			return nil, nil, false, fmt.Errorf("failure while generating %s CA certificate and key [%v]", caLabel, err)
 		}
 
-		if err = pkiutil.WriteCertAndKey(pkiDir, kubeadmconstants.CACertAndKeyBaseName, caCert, caKey); err != nil {
-			return fmt.Errorf("failure while saving CA certificate and key [%v]", err)
+		if err := pkiutil.WriteCertAndKey(pkiDir, baseName, caCert, caKey); err != nil {
+			return nil, nil, false, fmt.Errorf("failure while saving %s CA certificate and key [%v]", caLabel, err)
 		}
-		fmt.Println("[certificates] Generated CA certificate and key.")
+		fmt.Printf("[certificates] Generated %s CA certificate and key.\n", caLabel)
+		return caCert, caKey, false, nil
+	}
+}
+
+// createAPIServerKubeletClientCert creates the client certificate, signed by the root CA, that
+// the API server uses to authenticate against the kubelet
+func createAPIServerKubeletClientCert(caCert *x509.Certificate, caKey crypto.Signer, externalCA bool, keyConfig pkiutil.KeyConfig, pkiDir string) error {
+	config := certutil.Config{
+		CommonName:   "kube-apiserver-kubelet-client",
+		Organization: []string{"system:masters"},
+		Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
 	}
 
-	// If at least one of them exists, we should try to load them
-	// In the case that only one exists, there will most likely be an error anyway
-	if pkiutil.CertOrKeyExist(pkiDir, kubeadmconstants.APIServerCertAndKeyBaseName) {
-		// Try to load ca.crt and ca.key from the PKI directory
-		apiCert, apiKey, err := pkiutil.TryLoadCertAndKeyFromDisk(pkiDir, kubeadmconstants.APIServerCertAndKeyBaseName)
-		if err != nil || apiCert == nil || apiKey == nil {
+	return signOrRequestCert(pkiDir, kubeadmconstants.APIServerKubeletClientCertAndKeyBaseName, caCert, caKey, externalCA, config, keyConfig)
+}
+
+// signOrRequestCert creates the cert/key pair named baseName, reusing it if it already exists
+// on disk. When the signing CA is managed externally (externalCA), it writes a CSR and a
+// fresh private key instead of self-signing, and returns without error: the operator is
+// expected to get the CSR signed out of band and hand the result back to CollectSignedCerts.
+// When self-signing, it refuses to create a leaf whose configured key algorithm doesn't match
+// its signing CA's, so a CA loaded from disk under one algorithm can't silently end up with
+// leaves generated under another.
+func signOrRequestCert(pkiDir, baseName string, caCert *x509.Certificate, caKey crypto.Signer, externalCA bool, config certutil.Config, keyConfig pkiutil.KeyConfig) error {
+	// Check for a pending CSR before CertOrKeyExist: while a CSR is outstanding, WriteKey has
+	// already written <name>.key alongside it, which would otherwise make CertOrKeyExist
+	// report a (non-existent) pair as present and send us into TryLoadCertAndKeyFromDisk below,
+	// which fails because <name>.crt doesn't exist yet.
+	if externalCA && pkiutil.CSRExists(pkiDir, baseName) {
+		fmt.Printf("[certificates] Found an existing certificate request for %s, waiting for it to be signed.\n", baseName)
+		return nil
+	}
+
+	if pkiutil.CertOrKeyExist(pkiDir, baseName) {
+		cert, key, err := pkiutil.TryLoadCertAndKeyFromDisk(pkiDir, baseName)
+		if err != nil || cert == nil || key == nil {
 			return fmt.Errorf("certificate and/or key existed but they could not be loaded properly")
 		}
 
-		fmt.Println("[certificates] Using the existing API Server certificate and key.")
-	} else {
-		// The certificate and the key did NOT exist, let's generate them now
-		// TODO: Add a test case to verify that this cert has the x509.ExtKeyUsageServerAuth flag
-		config := certutil.Config{
-			CommonName: "kube-apiserver",
-			AltNames:   altNames,
-			Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
-		}
-		apiCert, apiKey, err := pkiutil.NewCertAndKey(caCert, caKey, config)
+		fmt.Printf("[certificates] Using the existing %s certificate and key.\n", baseName)
+		return nil
+	}
+
+	if externalCA {
+		csr, key, err := pkiutil.NewCSRAndKey(config, keyConfig)
 		if err != nil {
-			return fmt.Errorf("failure while creating API server key and certificate [%v]", err)
+			return fmt.Errorf("failure while creating %s certificate request [%v]", baseName, err)
 		}
 
-		if err = pkiutil.WriteCertAndKey(pkiDir, kubeadmconstants.APIServerCertAndKeyBaseName, apiCert, apiKey); err != nil {
-			return fmt.Errorf("failure while saving API server certificate and key [%v]", err)
+		if err := pkiutil.WriteCSR(pkiDir, baseName, csr); err != nil {
+			return fmt.Errorf("failure while saving %s certificate request [%v]", baseName, err)
+		}
+		if err := pkiutil.WriteKey(pkiDir, baseName, key); err != nil {
+			return fmt.Errorf("failure while saving %s private key [%v]", baseName, err)
 		}
-		fmt.Println("[certificates] Generated API server certificate and key.")
+		fmt.Printf("[certificates] Generated %s certificate request; submit it to your CA and place the signed certificate at the same path.\n", baseName)
+		return nil
 	}
 
-	fmt.Printf("[certificates] Valid certificates and keys now exist in %q\n", pkiDir)
+	leafAlgorithm := keyConfig.Algorithm
+	if leafAlgorithm == "" {
+		leafAlgorithm = pkiutil.RSA
+	}
+	if caAlgorithm := pkiutil.KeyAlgorithmOf(caKey); caAlgorithm != "" && caAlgorithm != leafAlgorithm {
+		return fmt.Errorf("cannot create %s with key algorithm %q: its signing CA's key algorithm is %q", baseName, leafAlgorithm, caAlgorithm)
+	}
+
+	cert, key, err := pkiutil.NewCertAndKey(caCert, caKey, config, keyConfig)
+	if err != nil {
+		return fmt.Errorf("failure while creating %s key and certificate [%v]", baseName, err)
+	}
+
+	if err := pkiutil.WriteCertAndKey(pkiDir, baseName, cert, key); err != nil {
+		return fmt.Errorf("failure while saving %s certificate and key [%v]", baseName, err)
+	}
+	fmt.Printf("[certificates] Generated %s certificate and key.\n", baseName)
+
+	return nil
+}
+
+// CollectSignedCerts promotes every pending CSR in pkiDir whose signed certificate has been
+// placed alongside it: it validates the signed certificate against its declared CA (root,
+// front-proxy or etcd) and the pending request before deleting the <name>.csr file. It stops
+// with an error at the first pending CSR that has no signed certificate next to it yet; the
+// requests before it have been promoted by then, the ones after it have not.
+func CollectSignedCerts(pkiDir string) error {
+	caCerts := map[string]*x509.Certificate{}
+
+	for _, asset := range expectedPKIAssets {
+		if asset.keyOnly || asset.isCA {
+			continue
+		}
+		if !pkiutil.CSRExists(pkiDir, asset.baseName) {
+			continue
+		}
+
+		caCert, ok := caCerts[asset.caBaseName]
+		if !ok {
+			var err error
+			caCert, err = pkiutil.TryLoadCertFromDisk(pkiDir, asset.caBaseName)
+			if err != nil {
+				return fmt.Errorf("couldn't load CA %q for %q: %v", asset.caBaseName, asset.baseName, err)
+			}
+			caCerts[asset.caBaseName] = caCert
+		}
+
+		if !pathExists(pkiutil.PathForCert(pkiDir, asset.baseName)) {
+			return fmt.Errorf("%s.csr is still pending: no signed certificate has been placed at %s.crt yet", asset.baseName, asset.baseName)
+		}
+
+		if err := pkiutil.PromoteSignedCert(pkiDir, asset.baseName, caCert); err != nil {
+			return fmt.Errorf("couldn't collect the signed certificate for %s: %v", asset.baseName, err)
+		}
+		fmt.Printf("[certificates] Collected the signed %s certificate.\n", asset.baseName)
+	}
+
+	return nil
+}
+
+// createFrontProxyPKIAssets creates the front-proxy CA and the front-proxy-client certificate
+// used by the aggregation layer. Like the root CA, the front-proxy CA may be externally
+// managed: if front-proxy-ca.crt exists without a matching key, front-proxy-client.csr is
+// written instead of a self-signed certificate.
+func createFrontProxyPKIAssets(pkiDir string, keyConfig pkiutil.KeyConfig) error {
+	frontProxyCACert, frontProxyCAKey, externalCA, err := loadOrGenerateCA(pkiDir, kubeadmconstants.FrontProxyCACertAndKeyBaseName, keyConfig, "front-proxy")
+	if err != nil {
+		return err
+	}
+
+	config := certutil.Config{
+		CommonName: "front-proxy-client",
+		Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	return signOrRequestCert(pkiDir, kubeadmconstants.FrontProxyClientCertAndKeyBaseName, frontProxyCACert, frontProxyCAKey, externalCA, config, keyConfig)
+}
+
+// createEtcdPKIAssets creates a dedicated CA for etcd, plus the server, peer and
+// apiserver-etcd-client leaf certificates signed by it. Like the root CA, the etcd CA may be
+// externally managed: if etcd/ca.crt exists without a matching key, each leaf's CSR is written
+// instead of a self-signed certificate.
+func createEtcdPKIAssets(apiServerAltNames certutil.AltNames, keyConfig pkiutil.KeyConfig, pkiDir string) error {
+	etcdCACert, etcdCAKey, externalCA, err := loadOrGenerateCA(pkiDir, kubeadmconstants.EtcdCACertAndKeyBaseName, keyConfig, "etcd")
+	if err != nil {
+		return err
+	}
+
+	etcdLeafAltNames := etcdAltNames(apiServerAltNames)
+
+	etcdLeaves := []struct {
+		baseName string
+		config   certutil.Config
+	}{
+		{
+			baseName: kubeadmconstants.EtcdServerCertAndKeyBaseName,
+			config: certutil.Config{
+				CommonName: "kube-etcd",
+				AltNames:   etcdLeafAltNames,
+				Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+			},
+		},
+		{
+			baseName: kubeadmconstants.EtcdPeerCertAndKeyBaseName,
+			config: certutil.Config{
+				CommonName: "kube-etcd-peer",
+				AltNames:   etcdLeafAltNames,
+				Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+			},
+		},
+		{
+			baseName: kubeadmconstants.APIServerEtcdClientCertAndKeyBaseName,
+			config: certutil.Config{
+				CommonName: "kube-apiserver-etcd-client",
+				Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			},
+		},
+	}
+
+	for _, leaf := range etcdLeaves {
+		if err := signOrRequestCert(pkiDir, leaf.baseName, etcdCACert, etcdCAKey, externalCA, leaf.config, keyConfig); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createServiceAccountKeyPair creates the standalone keypair kube-controller-manager uses to
+// sign, and the API server to verify, ServiceAccount tokens. It has no associated x509
+// certificate. kube-controller-manager's ServiceAccount token signer only supports RSA keys,
+// so any other algorithm is rejected here rather than failing later when the control plane
+// starts.
+func createServiceAccountKeyPair(keyConfig kubeadmapi.KeyConfig, pkiDir string) error {
+	if keyConfig.Algorithm != "" && keyConfig.Algorithm != string(pkiutil.RSA) {
+		return fmt.Errorf("the service account signing key must be RSA: kube-controller-manager does not support %s service account keys", keyConfig.Algorithm)
+	}
+
+	saPublicKeyPath := pkiutil.PathForPublicKey(pkiDir, kubeadmconstants.ServiceAccountKeyBaseName)
+	saPrivateKeyPath := pkiutil.PathForKey(pkiDir, kubeadmconstants.ServiceAccountKeyBaseName)
+
+	if _, err := os.Stat(saPrivateKeyPath); err == nil {
+		if _, err := os.Stat(saPublicKeyPath); err != nil {
+			return fmt.Errorf("service account private key existed but the public key could not be found")
+		}
+
+		fmt.Println("[certificates] Using the existing service account token signing key.")
+		return nil
+	}
+
+	saKeyConfig := toPKIKeyConfig(keyConfig)
+	if saKeyConfig.Algorithm == "" {
+		saKeyConfig.Algorithm = pkiutil.RSA
+	}
+
+	saKey, err := pkiutil.NewPrivateKey(saKeyConfig)
+	if err != nil {
+		return fmt.Errorf("failure while creating service account signing key [%v]", err)
+	}
+
+	if err := pkiutil.WriteKey(pkiDir, kubeadmconstants.ServiceAccountKeyBaseName, saKey); err != nil {
+		return fmt.Errorf("failure while saving service account signing key [%v]", err)
+	}
+
+	if err := pkiutil.WritePublicKey(pkiDir, kubeadmconstants.ServiceAccountKeyBaseName, saKey.Public()); err != nil {
+		return fmt.Errorf("failure while saving service account public key [%v]", err)
+	}
+	fmt.Println("[certificates] Generated service account token signing key.")
 
 	return nil
 }
@@ -166,3 +461,142 @@ func checkAltNamesExist(IPs []net.IP, DNSNames []string, altNames certutil.AltNa
 	}
 	return true
 }
+
+// pkiAsset describes one expected file on disk, and (for leaf certs) the CA it must chain to
+type pkiAsset struct {
+	baseName       string
+	caBaseName     string // empty for CAs and the keypair-only service account signing key
+	isCA           bool
+	keyOnly        bool               // true for the service account signing key, which has no certificate
+	requiredUsages []x509.ExtKeyUsage // extended key usages the leaf cert must carry
+	altNamesKind   string             // "", "apiserver" or "etcd": which required SAN set, if any, to check
+}
+
+// expectedPKIAssets lists every PKI asset CreatePKIAssets is responsible for producing
+var expectedPKIAssets = []pkiAsset{
+	{baseName: kubeadmconstants.CACertAndKeyBaseName, isCA: true},
+	{
+		baseName:       kubeadmconstants.APIServerCertAndKeyBaseName,
+		caBaseName:     kubeadmconstants.CACertAndKeyBaseName,
+		requiredUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		altNamesKind:   "apiserver",
+	},
+	{
+		baseName:       kubeadmconstants.APIServerKubeletClientCertAndKeyBaseName,
+		caBaseName:     kubeadmconstants.CACertAndKeyBaseName,
+		requiredUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	},
+	{baseName: kubeadmconstants.FrontProxyCACertAndKeyBaseName, isCA: true},
+	{
+		baseName:       kubeadmconstants.FrontProxyClientCertAndKeyBaseName,
+		caBaseName:     kubeadmconstants.FrontProxyCACertAndKeyBaseName,
+		requiredUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	},
+	{baseName: kubeadmconstants.EtcdCACertAndKeyBaseName, isCA: true},
+	{
+		baseName:       kubeadmconstants.EtcdServerCertAndKeyBaseName,
+		caBaseName:     kubeadmconstants.EtcdCACertAndKeyBaseName,
+		requiredUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		altNamesKind:   "etcd",
+	},
+	{
+		baseName:       kubeadmconstants.EtcdPeerCertAndKeyBaseName,
+		caBaseName:     kubeadmconstants.EtcdCACertAndKeyBaseName,
+		requiredUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		altNamesKind:   "etcd",
+	},
+	{
+		baseName:       kubeadmconstants.APIServerEtcdClientCertAndKeyBaseName,
+		caBaseName:     kubeadmconstants.EtcdCACertAndKeyBaseName,
+		requiredUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	},
+	{baseName: kubeadmconstants.ServiceAccountKeyBaseName, keyOnly: true},
+}
+
+// hasExtKeyUsages returns whether cert carries every extended key usage in required
+func hasExtKeyUsages(cert *x509.Certificate, required []x509.ExtKeyUsage) bool {
+	have := map[x509.ExtKeyUsage]bool{}
+	for _, usage := range cert.ExtKeyUsage {
+		have[usage] = true
+	}
+	for _, usage := range required {
+		if !have[usage] {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckPKIAssets validates that every file CreatePKIAssets is expected to have produced is
+// present, parseable, unexpired and, for leaf certificates, chains to its declared CA and
+// carries the SANs/EKUs it was created with.
+func CheckPKIAssets(cfg *kubeadmapi.MasterConfiguration, pkiDir string) error {
+	requiredAPIServerAltNames, err := apiServerAltNames(cfg)
+	if err != nil {
+		return err
+	}
+	requiredEtcdAltNames := etcdAltNames(requiredAPIServerAltNames)
+
+	loadedCAs := map[string]*x509.Certificate{}
+
+	for _, asset := range expectedPKIAssets {
+		if asset.keyOnly {
+			if !pathExists(pkiutil.PathForKey(pkiDir, asset.baseName)) || !pathExists(pkiutil.PathForPublicKey(pkiDir, asset.baseName)) {
+				return fmt.Errorf("service account signing key %q is missing", asset.baseName)
+			}
+			continue
+		}
+
+		if !pkiutil.CertOrKeyExist(pkiDir, asset.baseName) {
+			return fmt.Errorf("certificate and/or key for %q is missing", asset.baseName)
+		}
+
+		cert, _, err := pkiutil.TryLoadCertAndKeyFromDisk(pkiDir, asset.baseName)
+		if err != nil {
+			return fmt.Errorf("couldn't load %q: %v", asset.baseName, err)
+		}
+
+		if time.Now().After(cert.NotAfter) {
+			return fmt.Errorf("certificate %q expired on %v", asset.baseName, cert.NotAfter)
+		}
+
+		if asset.isCA {
+			if !cert.IsCA {
+				return fmt.Errorf("%q was expected to be a CA certificate", asset.baseName)
+			}
+			loadedCAs[asset.baseName] = cert
+			continue
+		}
+
+		ca, ok := loadedCAs[asset.caBaseName]
+		if !ok {
+			return fmt.Errorf("CA %q for %q was not validated before its leaf certificate", asset.caBaseName, asset.baseName)
+		}
+
+		if err := cert.CheckSignatureFrom(ca); err != nil {
+			return fmt.Errorf("%q does not chain to CA %q: %v", asset.baseName, asset.caBaseName, err)
+		}
+
+		if !hasExtKeyUsages(cert, asset.requiredUsages) {
+			return fmt.Errorf("%q is missing one or more of its required extended key usages %v", asset.baseName, asset.requiredUsages)
+		}
+
+		switch asset.altNamesKind {
+		case "apiserver":
+			if !checkAltNamesExist(cert.IPAddresses, cert.DNSNames, requiredAPIServerAltNames) {
+				return fmt.Errorf("%q is missing one or more of its required SANs", asset.baseName)
+			}
+		case "etcd":
+			if !checkAltNamesExist(cert.IPAddresses, cert.DNSNames, requiredEtcdAltNames) {
+				return fmt.Errorf("%q is missing one or more of its required SANs", asset.baseName)
+			}
+		}
+	}
+
+	return nil
+}
+
+func pathExists(p string) bool {
+	_, err := os.Stat(p)
+	return err == nil
+}
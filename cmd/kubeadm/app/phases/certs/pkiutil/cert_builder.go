@@ -0,0 +1,105 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkiutil
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"time"
+
+	certutil "k8s.io/client-go/pkg/util/cert"
+)
+
+const (
+	caCertificateValidity   = time.Hour * 24 * 365 * 10
+	leafCertificateValidity = time.Hour * 24 * 365
+)
+
+// newSerialNumber returns a random serial number suitable for a new certificate
+func newSerialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate a certificate serial number [%v]", err)
+	}
+	return serial, nil
+}
+
+// newSelfSignedCACert creates a self-signed CA certificate around the given key, key
+// algorithm agnostic
+func newSelfSignedCACert(config certutil.Config, key crypto.Signer) (*x509.Certificate, error) {
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   config.CommonName,
+			Organization: config.Organization,
+		},
+		NotBefore:             now.UTC(),
+		NotAfter:              now.Add(caCertificateValidity).UTC(),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create self-signed certificate [%v]", err)
+	}
+
+	return x509.ParseCertificate(der)
+}
+
+// newSignedCert creates a leaf certificate for config's key signed by caCert/caKey, key
+// algorithm agnostic on both ends
+func newSignedCert(config certutil.Config, key crypto.Signer, caCert *x509.Certificate, caKey crypto.Signer) (*x509.Certificate, error) {
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   config.CommonName,
+			Organization: config.Organization,
+		},
+		DNSNames:              config.AltNames.DNSNames,
+		IPAddresses:           config.AltNames.IPs,
+		NotBefore:             now.UTC(),
+		NotAfter:              now.Add(leafCertificateValidity).UTC(),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           config.Usages,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, caCert, key.Public(), caKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign certificate [%v]", err)
+	}
+
+	return x509.ParseCertificate(der)
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkiutil
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	certutil "k8s.io/client-go/pkg/util/cert"
+)
+
+// CertificateRequestBlockType is the PEM block type used for PKCS#10 certificate requests
+const CertificateRequestBlockType = "CERTIFICATE REQUEST"
+
+// NewCSRAndKey creates a new private key, generated according to keyConfig, and a matching
+// PKCS#10 certificate request carrying the subject and SANs described by config. config.Usages
+// is not included in the request, so the extended key usages of the signed certificate are
+// whatever the signing CA puts in it. It is used in place of NewCertAndKey when the CA that
+// will sign the certificate is managed externally.
+func NewCSRAndKey(config certutil.Config, keyConfig KeyConfig) (*x509.CertificateRequest, crypto.Signer, error) {
+	key, err := NewPrivateKey(keyConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create private key [%v]", err)
+	}
+
+	template := &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   config.CommonName,
+			Organization: config.Organization,
+		},
+		DNSNames:    config.AltNames.DNSNames,
+		IPAddresses: config.AltNames.IPs,
+	}
+
+	csrBytes, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create certificate request [%v]", err)
+	}
+
+	csr, err := x509.ParseCertificateRequest(csrBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse the certificate request it just created [%v]", err)
+	}
+
+	return csr, key, nil
+}
+
+// WriteCSR writes a PKCS#10 certificate request to <pkiPath>/<name>.csr
+func WriteCSR(pkiPath, name string, csr *x509.CertificateRequest) error {
+	if csr == nil {
+		return fmt.Errorf("certificate request is nil when attempting to write to file")
+	}
+
+	csrPath := pathForCSR(pkiPath, name)
+	if err := ensureParentDir(csrPath); err != nil {
+		return err
+	}
+
+	block := pem.EncodeToMemory(&pem.Block{Type: CertificateRequestBlockType, Bytes: csr.Raw})
+	if err := ioutil.WriteFile(csrPath, block, 0644); err != nil {
+		return fmt.Errorf("unable to write certificate request to file %q: [%v]", csrPath, err)
+	}
+
+	return nil
+}
+
+// TryLoadCSRFromDisk loads a PKCS#10 certificate request previously written with WriteCSR
+func TryLoadCSRFromDisk(pkiPath, name string) (*x509.CertificateRequest, error) {
+	csrPath := pathForCSR(pkiPath, name)
+
+	csrBytes, err := ioutil.ReadFile(csrPath)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read the certificate request file %s: %v", csrPath, err)
+	}
+
+	block, _ := pem.Decode(csrBytes)
+	if block == nil || block.Type != CertificateRequestBlockType {
+		return nil, fmt.Errorf("%s does not contain a valid certificate request", csrPath)
+	}
+
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse the certificate request file %s: %v", csrPath, err)
+	}
+
+	return csr, nil
+}
+
+// CSRExists returns whether a pending <name>.csr file exists in pkiPath
+func CSRExists(pkiPath, name string) bool {
+	_, err := os.Stat(pathForCSR(pkiPath, name))
+	return err == nil
+}
+
+func pathForCSR(pkiPath, name string) string {
+	return path.Join(pkiPath, fmt.Sprintf("%s.csr", name))
+}
+
+// PromoteSignedCert validates the <name>.crt an external CA has placed next to a pending
+// <name>.csr: it must be signed by caCert, embed the same public key the CSR was generated
+// with, and carry every SAN the CSR requested. Once validated, the pending .csr is removed.
+func PromoteSignedCert(pkiPath, name string, caCert *x509.Certificate) error {
+	csr, err := TryLoadCSRFromDisk(pkiPath, name)
+	if err != nil {
+		return fmt.Errorf("couldn't load pending certificate request for %q: %v", name, err)
+	}
+
+	cert, err := TryLoadCertFromDisk(pkiPath, name)
+	if err != nil {
+		return fmt.Errorf("couldn't load the signed certificate for %q: %v", name, err)
+	}
+
+	type comparablePublicKey interface {
+		Equal(crypto.PublicKey) bool
+	}
+	certKey, ok := cert.PublicKey.(comparablePublicKey)
+	if !ok {
+		return fmt.Errorf("the signed certificate for %q has an unsupported public key type %T", name, cert.PublicKey)
+	}
+	if !certKey.Equal(csr.PublicKey) {
+		return fmt.Errorf("the signed certificate for %q does not match the public key of the pending request", name)
+	}
+
+	if err := cert.CheckSignatureFrom(caCert); err != nil {
+		return fmt.Errorf("the signed certificate for %q does not chain to the expected CA: %v", name, err)
+	}
+
+	existingDNS := map[string]bool{}
+	for _, dnsName := range cert.DNSNames {
+		existingDNS[dnsName] = true
+	}
+	for _, dnsName := range csr.DNSNames {
+		if !existingDNS[dnsName] {
+			return fmt.Errorf("the signed certificate for %q is missing requested SAN %q", name, dnsName)
+		}
+	}
+	for _, ip := range csr.IPAddresses {
+		found := false
+		for _, certIP := range cert.IPAddresses {
+			if certIP.Equal(ip) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("the signed certificate for %q is missing requested SAN %q", name, ip)
+		}
+	}
+
+	if err := os.Remove(pathForCSR(pkiPath, name)); err != nil {
+		return fmt.Errorf("couldn't remove the pending certificate request for %q: %v", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkiutil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+const (
+	// RSAPrivateKeyBlockType is the PEM block type for a PKCS#1 RSA private key
+	RSAPrivateKeyBlockType = "RSA PRIVATE KEY"
+	// ECPrivateKeyBlockType is the PEM block type for a SEC1 EC private key
+	ECPrivateKeyBlockType = "EC PRIVATE KEY"
+	// PrivateKeyBlockType is the PEM block type for a PKCS#8 private key, used here for Ed25519
+	PrivateKeyBlockType = "PRIVATE KEY"
+	// PublicKeyBlockType is the PEM block type for a PKIX public key
+	PublicKeyBlockType = "PUBLIC KEY"
+)
+
+// EncodePrivateKeyPEM marshals key to the PEM encoding native to its algorithm: PKCS#1 for
+// RSA, SEC1 for ECDSA, PKCS#8 for Ed25519.
+func EncodePrivateKeyPEM(key crypto.Signer) ([]byte, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return pem.EncodeToMemory(&pem.Block{Type: RSAPrivateKeyBlockType, Bytes: x509.MarshalPKCS1PrivateKey(k)}), nil
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal ECDSA private key [%v]", err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: ECPrivateKeyBlockType, Bytes: der}), nil
+	case ed25519.PrivateKey:
+		der, err := x509.MarshalPKCS8PrivateKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal Ed25519 private key [%v]", err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: PrivateKeyBlockType, Bytes: der}), nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
+
+// ParsePrivateKeyPEM parses a PEM-encoded private key in any of the formats EncodePrivateKeyPEM
+// produces
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in private key file")
+	}
+
+	switch block.Type {
+	case RSAPrivateKeyBlockType:
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case ECPrivateKeyBlockType:
+		return x509.ParseECPrivateKey(block.Bytes)
+	case PrivateKeyBlockType:
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse PKCS#8 private key [%v]", err)
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("PKCS#8 key of type %T does not implement crypto.Signer", key)
+		}
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("unknown private key PEM block type %q", block.Type)
+	}
+}
+
+// EncodePublicKeyPEM marshals a public key to PKIX/PEM, regardless of its underlying algorithm
+func EncodePublicKeyPEM(key crypto.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal public key [%v]", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: PublicKeyBlockType, Bytes: der}), nil
+}
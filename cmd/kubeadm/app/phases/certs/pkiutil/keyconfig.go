@@ -0,0 +1,115 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkiutil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+)
+
+// KeyAlgorithm identifies which public-key algorithm a KeyConfig asks for
+type KeyAlgorithm string
+
+const (
+	// RSA is the default key algorithm
+	RSA KeyAlgorithm = "rsa"
+	// ECDSA generates smaller keys and certs and signs/verifies faster than RSA
+	ECDSA KeyAlgorithm = "ecdsa"
+	// Ed25519 is the fastest of the three, but is not supported as a ServiceAccount
+	// signing key by kube-controller-manager
+	Ed25519 KeyAlgorithm = "ed25519"
+
+	defaultRSABits = 2048
+	defaultCurve   = "P256"
+)
+
+// KeyConfig describes what key algorithm and size/curve a certificate or CSR should be
+// generated with. The zero value means 2048-bit RSA.
+type KeyConfig struct {
+	Algorithm KeyAlgorithm
+	// Bits is the RSA modulus size (2048, 3072 or 4096). Only used when Algorithm is RSA.
+	Bits int
+	// Curve is the ECDSA curve ("P256" or "P384"). Only used when Algorithm is ECDSA.
+	Curve string
+}
+
+// NewPrivateKey generates a new private key according to cfg
+func NewPrivateKey(cfg KeyConfig) (crypto.Signer, error) {
+	switch cfg.Algorithm {
+	case "", RSA:
+		bits := cfg.Bits
+		if bits == 0 {
+			bits = defaultRSABits
+		}
+		if bits < defaultRSABits {
+			return nil, fmt.Errorf("RSA key size %d is too small: the minimum supported size is %d bits", bits, defaultRSABits)
+		}
+		key, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create RSA private key [%v]", err)
+		}
+		return key, nil
+	case ECDSA:
+		curve := cfg.Curve
+		if curve == "" {
+			curve = defaultCurve
+		}
+		var c elliptic.Curve
+		switch curve {
+		case "P256":
+			c = elliptic.P256()
+		case "P384":
+			c = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported ECDSA curve %q: only P256 and P384 are supported", curve)
+		}
+		key, err := ecdsa.GenerateKey(c, rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create ECDSA private key [%v]", err)
+		}
+		return key, nil
+	case Ed25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create Ed25519 private key [%v]", err)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key algorithm %q", cfg.Algorithm)
+	}
+}
+
+// KeyAlgorithmOf returns the KeyAlgorithm of an already-generated or loaded key, so it can be
+// compared against the KeyAlgorithm a new certificate is about to be created with. Returns ""
+// for a key type not produced by NewPrivateKey.
+func KeyAlgorithmOf(key crypto.Signer) KeyAlgorithm {
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		return RSA
+	case *ecdsa.PrivateKey:
+		return ECDSA
+	case ed25519.PrivateKey:
+		return Ed25519
+	default:
+		return ""
+	}
+}
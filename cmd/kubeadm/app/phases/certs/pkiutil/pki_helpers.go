@@ -0,0 +1,279 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkiutil
+
+import (
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	certutil "k8s.io/client-go/pkg/util/cert"
+)
+
+// NewCertificateAuthority creates a new self-signed CA certificate and a private key
+// generated according to keyConfig
+func NewCertificateAuthority(keyConfig KeyConfig) (*x509.Certificate, crypto.Signer, error) {
+	key, err := NewPrivateKey(keyConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create private key [%v]", err)
+	}
+
+	config := certutil.Config{
+		CommonName: "kubernetes",
+	}
+
+	cert, err := newSelfSignedCACert(config, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create self-signed certificate [%v]", err)
+	}
+
+	return cert, key, nil
+}
+
+// NewCertAndKey creates a new certificate and a private key generated according to keyConfig,
+// signed by the given certificate authority
+func NewCertAndKey(caCert *x509.Certificate, caKey crypto.Signer, config certutil.Config, keyConfig KeyConfig) (*x509.Certificate, crypto.Signer, error) {
+	key, err := NewPrivateKey(keyConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create private key [%v]", err)
+	}
+
+	cert, err := newSignedCert(config, key, caCert, caKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to sign certificate [%v]", err)
+	}
+
+	return cert, key, nil
+}
+
+// WriteCertAndKey stores certificate and key at the specified location
+func WriteCertAndKey(pkiPath string, name string, cert *x509.Certificate, key crypto.Signer) error {
+	if err := WriteKey(pkiPath, name, key); err != nil {
+		return fmt.Errorf("couldn't write key: %v", err)
+	}
+
+	if err := WriteCert(pkiPath, name, cert); err != nil {
+		return fmt.Errorf("couldn't write cert: %v", err)
+	}
+
+	return nil
+}
+
+// WriteCert stores the given certificate at the given location
+func WriteCert(pkiPath, name string, cert *x509.Certificate) error {
+	if cert == nil {
+		return fmt.Errorf("certificate is nil when attempting to write to file")
+	}
+
+	certificatePath := pathForCert(pkiPath, name)
+	if err := ensureParentDir(certificatePath); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(certificatePath, certutil.EncodeCertPEM(cert), 0644); err != nil {
+		return fmt.Errorf("unable to write certificate to file %q: [%v]", certificatePath, err)
+	}
+
+	return nil
+}
+
+// WriteKey stores the given key at the given location, PEM-encoded according to its algorithm
+func WriteKey(pkiPath, name string, key crypto.Signer) error {
+	if key == nil {
+		return fmt.Errorf("private key is nil when attempting to write to file")
+	}
+
+	encoded, err := EncodePrivateKeyPEM(key)
+	if err != nil {
+		return err
+	}
+
+	privateKeyPath := pathForKey(pkiPath, name)
+	if err := ensureParentDir(privateKeyPath); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(privateKeyPath, encoded, 0600); err != nil {
+		return fmt.Errorf("unable to write private key to file %q: [%v]", privateKeyPath, err)
+	}
+
+	return nil
+}
+
+// WritePublicKey stores the given public key at the given location
+func WritePublicKey(pkiPath, name string, key crypto.PublicKey) error {
+	publicKeyBytes, err := EncodePublicKeyPEM(key)
+	if err != nil {
+		return err
+	}
+
+	publicKeyPath := pathForPublicKey(pkiPath, name)
+	if err := ensureParentDir(publicKeyPath); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(publicKeyPath, publicKeyBytes, 0644); err != nil {
+		return fmt.Errorf("unable to write public key to file %q: [%v]", publicKeyPath, err)
+	}
+
+	return nil
+}
+
+// CertOrKeyExist returns a boolean whether the cert or the key exists
+func CertOrKeyExist(pkiPath, name string) bool {
+	certificatePath, privateKeyPath := pathsForCertAndKey(pkiPath, name)
+
+	_, certErr := os.Stat(certificatePath)
+	_, keyErr := os.Stat(privateKeyPath)
+
+	if os.IsNotExist(certErr) && os.IsNotExist(keyErr) {
+		// The cert or the key did not exist
+		return false
+	}
+
+	// Both files exist or one of them
+	return true
+}
+
+// TryLoadCertAndKeyFromDisk tries to load a cert and a key from the disk and validates that they are valid
+func TryLoadCertAndKeyFromDisk(pkiPath, name string) (*x509.Certificate, crypto.Signer, error) {
+	cert, err := TryLoadCertFromDisk(pkiPath, name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failure loading certificate for %s: %v", name, err)
+	}
+
+	key, err := TryLoadKeyFromDisk(pkiPath, name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failure loading key for %s: %v", name, err)
+	}
+
+	return cert, key, nil
+}
+
+// TryLoadCertFromDisk tries to load the cert from the disk
+func TryLoadCertFromDisk(pkiPath, name string) (*x509.Certificate, error) {
+	certificatePath := pathForCert(pkiPath, name)
+
+	certs, err := certutil.CertsFromFile(certificatePath)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load the certificate file %s: %v", certificatePath, err)
+	}
+
+	// We are only putting one certificate in the certificate pem file, so it's safe to just pick the first one
+	// TODO: Support multiple certs here in order to be able to rotate certs
+	cert := certs[0]
+
+	return cert, nil
+}
+
+// TryLoadKeyFromDisk tries to load the key from the disk and validates that it is valid. RSA,
+// ECDSA and Ed25519 keys are all accepted.
+func TryLoadKeyFromDisk(pkiPath, name string) (crypto.Signer, error) {
+	privateKeyPath := pathForKey(pkiPath, name)
+
+	data, err := ioutil.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read the private key file %s: %v", privateKeyPath, err)
+	}
+
+	key, err := ParsePrivateKeyPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load the private key file %s: %v", privateKeyPath, err)
+	}
+
+	return key, nil
+}
+
+func ensureParentDir(p string) error {
+	return os.MkdirAll(path.Dir(p), 0755)
+}
+
+func pathsForCertAndKey(pkiPath, name string) (string, string) {
+	return pathForCert(pkiPath, name), pathForKey(pkiPath, name)
+}
+
+func pathForCert(pkiPath, name string) string {
+	return path.Join(pkiPath, fmt.Sprintf("%s.crt", name))
+}
+
+func pathForKey(pkiPath, name string) string {
+	return path.Join(pkiPath, fmt.Sprintf("%s.key", name))
+}
+
+func pathForPublicKey(pkiPath, name string) string {
+	return path.Join(pkiPath, fmt.Sprintf("%s.pub", name))
+}
+
+// PathForCert returns the path to the certificate file with the given base name
+func PathForCert(pkiPath, name string) string {
+	return pathForCert(pkiPath, name)
+}
+
+// PathForKey returns the path to the private key file with the given base name
+func PathForKey(pkiPath, name string) string {
+	return pathForKey(pkiPath, name)
+}
+
+// PathForPublicKey returns the path to the public key file with the given base name
+func PathForPublicKey(pkiPath, name string) string {
+	return pathForPublicKey(pkiPath, name)
+}
+
+// NewCertFromCA signs the public key of key using caCert/caKey, producing a fresh certificate
+// with the same subject, SANs and extended key usages as oldCert but a renewed validity
+// window. The public key embedded in oldCert is not used, so callers that don't want to
+// rotate the key pair pass the key they loaded oldCert alongside.
+func NewCertFromCA(caCert *x509.Certificate, caKey crypto.Signer, oldCert *x509.Certificate, key crypto.Signer) (*x509.Certificate, error) {
+	config := certutil.Config{
+		CommonName:   oldCert.Subject.CommonName,
+		Organization: oldCert.Subject.Organization,
+		AltNames: certutil.AltNames{
+			DNSNames: oldCert.DNSNames,
+			IPs:      oldCert.IPAddresses,
+		},
+		Usages: oldCert.ExtKeyUsage,
+	}
+
+	cert, err := newSignedCert(config, key, caCert, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign renewed certificate [%v]", err)
+	}
+
+	return cert, nil
+}
+
+// BackupCertAndKey copies the current <name>.crt and <name>.key files next to themselves
+// with a ".bak" suffix, so an operator can roll back a failed or unwanted renewal. It is a
+// no-op for files that don't currently exist.
+func BackupCertAndKey(pkiPath, name string) error {
+	for _, p := range []string{pathForCert(pkiPath, name), pathForKey(pkiPath, name)} {
+		if _, err := os.Stat(p); os.IsNotExist(err) {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("couldn't read %q to back it up: %v", p, err)
+		}
+
+		if err := ioutil.WriteFile(p+".bak", data, 0600); err != nil {
+			return fmt.Errorf("couldn't write backup file %q: %v", p+".bak", err)
+		}
+	}
+
+	return nil
+}
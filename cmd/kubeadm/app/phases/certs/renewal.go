@@ -0,0 +1,294 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"text/tabwriter"
+	"time"
+
+	setutil "k8s.io/apimachinery/pkg/util/sets"
+	certutil "k8s.io/client-go/pkg/util/cert"
+	kubeadmapi "k8s.io/kubernetes/cmd/kubeadm/app/apis/kubeadm"
+	kubeadmconstants "k8s.io/kubernetes/cmd/kubeadm/app/constants"
+	"k8s.io/kubernetes/cmd/kubeadm/app/phases/certs/pkiutil"
+)
+
+// defaultRenewalThreshold is how soon before expiry a leaf certificate is considered due
+// for renewal when RenewOptions.Threshold is left at its zero value
+const defaultRenewalThreshold = 30 * 24 * time.Hour
+
+// CertInfo is a snapshot of one certificate's metadata, as produced by InspectPKIAssets
+type CertInfo struct {
+	BaseName          string
+	Subject           string
+	Issuer            string
+	NotBefore         time.Time
+	NotAfter          time.Time
+	RemainingLifetime time.Duration
+	DNSNames          []string
+	IPAddresses       []net.IP
+	ExtKeyUsages      []x509.ExtKeyUsage
+	IsCA              bool
+	SignedByOnDiskCA  bool
+}
+
+// RenewOptions controls how RenewPKIAssets decides what to renew
+type RenewOptions struct {
+	// Threshold is how close to expiry a certificate must be before it's renewed.
+	// Defaults to 30 days if zero.
+	Threshold time.Duration
+	// RotateKey, if true, generates a new private key for each renewed certificate instead
+	// of re-signing the existing one.
+	RotateKey bool
+	// Force renews a certificate even if its SANs no longer match cfg, instead of reporting
+	// the mismatch in RenewReport.SANDiffs and leaving the certificate untouched.
+	Force bool
+}
+
+// SANDiff describes how a certificate's SANs differ from what the current MasterConfiguration
+// requires
+type SANDiff struct {
+	MissingDNSNames []string
+	ExtraDNSNames   []string
+	MissingIPs      []net.IP
+	ExtraIPs        []net.IP
+}
+
+func (d SANDiff) isEmpty() bool {
+	return len(d.MissingDNSNames) == 0 && len(d.ExtraDNSNames) == 0 && len(d.MissingIPs) == 0 && len(d.ExtraIPs) == 0
+}
+
+// RenewReport summarizes the outcome of a RenewPKIAssets call
+type RenewReport struct {
+	Renewed  []string
+	Skipped  []string
+	SANDiffs map[string]SANDiff
+}
+
+// InspectPKIAssets walks pkiDir and returns metadata for every known PKI asset that is
+// present, including whether it chains to the CA it's supposed to
+func InspectPKIAssets(pkiDir string) ([]CertInfo, error) {
+	var infos []CertInfo
+	loadedCAs := map[string]*x509.Certificate{}
+
+	for _, asset := range expectedPKIAssets {
+		if asset.keyOnly {
+			continue
+		}
+		if !pkiutil.CertOrKeyExist(pkiDir, asset.baseName) {
+			continue
+		}
+
+		cert, err := pkiutil.TryLoadCertFromDisk(pkiDir, asset.baseName)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't load %q: %v", asset.baseName, err)
+		}
+
+		info := CertInfo{
+			BaseName:          asset.baseName,
+			Subject:           cert.Subject.String(),
+			Issuer:            cert.Issuer.String(),
+			NotBefore:         cert.NotBefore,
+			NotAfter:          cert.NotAfter,
+			RemainingLifetime: time.Until(cert.NotAfter),
+			DNSNames:          cert.DNSNames,
+			IPAddresses:       cert.IPAddresses,
+			ExtKeyUsages:      cert.ExtKeyUsage,
+			IsCA:              cert.IsCA,
+		}
+
+		if asset.isCA {
+			loadedCAs[asset.baseName] = cert
+			info.SignedByOnDiskCA = cert.CheckSignatureFrom(cert) == nil
+		} else if ca, ok := loadedCAs[asset.caBaseName]; ok {
+			info.SignedByOnDiskCA = cert.CheckSignatureFrom(ca) == nil
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// RenewPKIAssets re-signs every leaf certificate in pkiDir whose remaining lifetime has
+// fallen below opts.Threshold. It refuses to renew anything signed by an expired on-disk CA,
+// and refuses to renew a leaf certificate whose SANs no longer match cfg unless opts.Force
+// is set; in that case the mismatch is reported via RenewReport.SANDiffs instead.
+func RenewPKIAssets(cfg *kubeadmapi.MasterConfiguration, pkiDir string, opts RenewOptions) (*RenewReport, error) {
+	threshold := opts.Threshold
+	if threshold == 0 {
+		threshold = defaultRenewalThreshold
+	}
+
+	requiredAltNames, err := apiServerAltNames(cfg)
+	if err != nil {
+		return nil, err
+	}
+	requiredEtcdAltNames := etcdAltNames(requiredAltNames)
+
+	keyConfig := toPKIKeyConfig(cfg.KeyConfig)
+
+	report := &RenewReport{SANDiffs: map[string]SANDiff{}}
+	loadedCAs := map[string]*x509.Certificate{}
+	loadedCAKeys := map[string]crypto.Signer{}
+
+	for _, asset := range expectedPKIAssets {
+		if asset.keyOnly || !asset.isCA {
+			continue
+		}
+		if !pkiutil.CertOrKeyExist(pkiDir, asset.baseName) {
+			continue
+		}
+
+		caCert, caKey, err := pkiutil.TryLoadCertAndKeyFromDisk(pkiDir, asset.baseName)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't load CA %q: %v", asset.baseName, err)
+		}
+		if time.Now().After(caCert.NotAfter) {
+			return nil, fmt.Errorf("CA %q is expired and must be replaced before its leaf certificates can be renewed", asset.baseName)
+		}
+
+		loadedCAs[asset.baseName] = caCert
+		loadedCAKeys[asset.baseName] = caKey
+	}
+
+	for _, asset := range expectedPKIAssets {
+		if asset.keyOnly || asset.isCA {
+			continue
+		}
+		if !pkiutil.CertOrKeyExist(pkiDir, asset.baseName) {
+			continue
+		}
+
+		cert, key, err := pkiutil.TryLoadCertAndKeyFromDisk(pkiDir, asset.baseName)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't load %q: %v", asset.baseName, err)
+		}
+
+		if time.Until(cert.NotAfter) >= threshold {
+			report.Skipped = append(report.Skipped, asset.baseName)
+			continue
+		}
+
+		// Only the API server and etcd server/peer certs carry the configuration-derived
+		// SANs; the rest (kubelet-client, front-proxy-client, apiserver-etcd-client) are
+		// CN-only and have nothing to compare against cfg.
+		var requiredCertAltNames certutil.AltNames
+		switch asset.baseName {
+		case kubeadmconstants.APIServerCertAndKeyBaseName:
+			requiredCertAltNames = requiredAltNames
+		case kubeadmconstants.EtcdServerCertAndKeyBaseName, kubeadmconstants.EtcdPeerCertAndKeyBaseName:
+			requiredCertAltNames = requiredEtcdAltNames
+		}
+		if requiredCertAltNames.DNSNames != nil || requiredCertAltNames.IPs != nil {
+			if diff := diffAltNames(cert, requiredCertAltNames); !diff.isEmpty() && !opts.Force {
+				report.SANDiffs[asset.baseName] = diff
+				continue
+			}
+		}
+
+		caCert, caKey := loadedCAs[asset.caBaseName], loadedCAKeys[asset.caBaseName]
+
+		if err := pkiutil.BackupCertAndKey(pkiDir, asset.baseName); err != nil {
+			return nil, fmt.Errorf("couldn't back up %q before renewal: %v", asset.baseName, err)
+		}
+
+		var newCert *x509.Certificate
+		var newKey crypto.Signer
+		if opts.RotateKey {
+			config := certutil.Config{
+				CommonName:   cert.Subject.CommonName,
+				Organization: cert.Subject.Organization,
+				AltNames:     certutil.AltNames{DNSNames: cert.DNSNames, IPs: cert.IPAddresses},
+				Usages:       cert.ExtKeyUsage,
+			}
+			newCert, newKey, err = pkiutil.NewCertAndKey(caCert, caKey, config, keyConfig)
+		} else {
+			newCert, err = pkiutil.NewCertFromCA(caCert, caKey, cert, key)
+			newKey = key
+		}
+		if err != nil {
+			return nil, fmt.Errorf("couldn't renew %q: %v", asset.baseName, err)
+		}
+
+		if err := pkiutil.WriteCertAndKey(pkiDir, asset.baseName, newCert, newKey); err != nil {
+			return nil, fmt.Errorf("couldn't write renewed %q: %v", asset.baseName, err)
+		}
+
+		report.Renewed = append(report.Renewed, asset.baseName)
+	}
+
+	return report, nil
+}
+
+// diffAltNames reports how cert's SANs differ from required
+func diffAltNames(cert *x509.Certificate, required certutil.AltNames) SANDiff {
+	existingDNS := setutil.NewString(cert.DNSNames...)
+	requiredDNS := setutil.NewString(required.DNSNames...)
+
+	var diff SANDiff
+	diff.MissingDNSNames = requiredDNS.Difference(existingDNS).List()
+	diff.ExtraDNSNames = existingDNS.Difference(requiredDNS).List()
+
+	for _, ip := range required.IPs {
+		if !containsIP(cert.IPAddresses, ip) {
+			diff.MissingIPs = append(diff.MissingIPs, ip)
+		}
+	}
+	for _, ip := range cert.IPAddresses {
+		if !containsIP(required.IPs, ip) {
+			diff.ExtraIPs = append(diff.ExtraIPs, ip)
+		}
+	}
+
+	return diff
+}
+
+func containsIP(ips []net.IP, ip net.IP) bool {
+	for _, candidate := range ips {
+		if candidate.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatCertExpirationTable renders InspectPKIAssets output as a table, in the style of
+// `kubeadm alpha certs check-expiration`
+func FormatCertExpirationTable(infos []CertInfo) string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "CERTIFICATE\tEXPIRES\tRESIDUAL TIME\tCERTIFICATE AUTHORITY\tSIGNED BY CA ON DISK")
+	for _, info := range infos {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%v\t%v\n",
+			info.BaseName,
+			info.NotAfter.Format("Jan 02, 2006 15:04 MST"),
+			info.RemainingLifetime.Round(time.Hour),
+			info.IsCA,
+			info.SignedByOnDiskCA,
+		)
+	}
+
+	w.Flush()
+	return buf.String()
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	certutil "k8s.io/client-go/pkg/util/cert"
+	kubeadmapi "k8s.io/kubernetes/cmd/kubeadm/app/apis/kubeadm"
+	kubeadmconstants "k8s.io/kubernetes/cmd/kubeadm/app/constants"
+	"k8s.io/kubernetes/cmd/kubeadm/app/phases/certs/pkiutil"
+)
+
+// clusterName is the name kubeadm gives the cluster entry in every kubeconfig it writes
+const clusterName = "kubernetes"
+
+// kubeConfigSpec describes one of the control-plane component kubeconfig files kubeadm owns
+type kubeConfigSpec struct {
+	fileName     string
+	clientName   string
+	organization []string
+}
+
+// clientSpecs returns the kubeconfig files CreateKubeconfigFiles is responsible for. The
+// kubelet's client name embeds the local node name, so it can't be a package-level constant.
+func clientSpecs(nodeName string) []kubeConfigSpec {
+	return []kubeConfigSpec{
+		{
+			fileName:     kubeadmconstants.AdminKubeConfigFileName,
+			clientName:   "kubernetes-admin",
+			organization: []string{"system:masters"},
+		},
+		{
+			fileName:     kubeadmconstants.KubeletKubeConfigFileName,
+			clientName:   fmt.Sprintf("system:node:%s", nodeName),
+			organization: []string{"system:nodes"},
+		},
+		{
+			fileName:   kubeadmconstants.ControllerManagerKubeConfigFileName,
+			clientName: "system:kube-controller-manager",
+		},
+		{
+			fileName:   kubeadmconstants.SchedulerKubeConfigFileName,
+			clientName: "system:kube-scheduler",
+		},
+	}
+}
+
+// CreateKubeconfigFiles creates the admin, kubelet, controller-manager and scheduler
+// kubeconfig files in outDir. Each embeds the CA certificate found in pkiDir, the API server
+// endpoint derived from cfg, and a freshly minted client certificate signed by ca.{crt,key}
+// with the CN/O appropriate for that component. A kubeconfig already present in outDir is
+// reused as-is as long as its embedded client certificate is still valid and chains to the
+// CA on disk; otherwise it's regenerated.
+func CreateKubeconfigFiles(cfg *kubeadmapi.MasterConfiguration, pkiDir, outDir string) error {
+	caCert, caKey, err := pkiutil.TryLoadCertAndKeyFromDisk(pkiDir, kubeadmconstants.CACertAndKeyBaseName)
+	if err != nil {
+		return fmt.Errorf("couldn't load the CA certificate and key: %v", err)
+	}
+
+	if len(cfg.API.AdvertiseAddresses) == 0 {
+		return fmt.Errorf("cannot create kubeconfig files: no API server advertise address configured")
+	}
+	serverURL := fmt.Sprintf("https://%s:%d", cfg.API.AdvertiseAddresses[0], cfg.API.BindPort)
+
+	nodeName, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("couldn't determine the local node name: %v", err)
+	}
+
+	keyConfig := pkiutil.KeyConfig{
+		Algorithm: pkiutil.KeyAlgorithm(cfg.KeyConfig.Algorithm),
+		Bits:      cfg.KeyConfig.Bits,
+		Curve:     cfg.KeyConfig.Curve,
+	}
+
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("couldn't create kubeconfig directory %q: %v", outDir, err)
+	}
+
+	for _, spec := range clientSpecs(nodeName) {
+		path := filepath.Join(outDir, spec.fileName)
+
+		if kubeconfigIsValid(path, caCert) {
+			fmt.Printf("[kubeconfig] Using the existing %s.\n", spec.fileName)
+			continue
+		}
+
+		config := certutil.Config{
+			CommonName:   spec.clientName,
+			Organization: spec.organization,
+			Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}
+		clientCert, clientKey, err := pkiutil.NewCertAndKey(caCert, caKey, config, keyConfig)
+		if err != nil {
+			return fmt.Errorf("failure while creating the %s client certificate [%v]", spec.clientName, err)
+		}
+		clientKeyPEM, err := pkiutil.EncodePrivateKeyPEM(clientKey)
+		if err != nil {
+			return fmt.Errorf("failure while encoding the %s client key [%v]", spec.clientName, err)
+		}
+
+		kubeconfig := buildKubeConfig(serverURL, spec.clientName, certutil.EncodeCertPEM(caCert), certutil.EncodeCertPEM(clientCert), clientKeyPEM)
+		if err := clientcmd.WriteToFile(*kubeconfig, path); err != nil {
+			return fmt.Errorf("failure while saving %s [%v]", spec.fileName, err)
+		}
+		fmt.Printf("[kubeconfig] Wrote %s.\n", spec.fileName)
+	}
+
+	return nil
+}
+
+// buildKubeConfig assembles a single-cluster, single-context kubeconfig embedding the given
+// CA and client certificate/key data
+func buildKubeConfig(serverURL, clientName string, caCertPEM, clientCertPEM, clientKeyPEM []byte) *clientcmdapi.Config {
+	config := clientcmdapi.NewConfig()
+
+	config.Clusters[clusterName] = &clientcmdapi.Cluster{
+		Server:                   serverURL,
+		CertificateAuthorityData: caCertPEM,
+	}
+	config.AuthInfos[clientName] = &clientcmdapi.AuthInfo{
+		ClientCertificateData: clientCertPEM,
+		ClientKeyData:         clientKeyPEM,
+	}
+	config.Contexts[clientName+"@"+clusterName] = &clientcmdapi.Context{
+		Cluster:  clusterName,
+		AuthInfo: clientName,
+	}
+	config.CurrentContext = clientName + "@" + clusterName
+
+	return config
+}
+
+// kubeconfigIsValid returns true if path is a kubeconfig whose embedded client certificate is
+// still valid and chains to caCert, meaning CreateKubeconfigFiles can leave it untouched
+func kubeconfigIsValid(path string, caCert *x509.Certificate) bool {
+	existing, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		return false
+	}
+
+	context, ok := existing.Contexts[existing.CurrentContext]
+	if !ok || context == nil {
+		return false
+	}
+
+	authInfo, ok := existing.AuthInfos[context.AuthInfo]
+	if !ok || authInfo == nil {
+		return false
+	}
+
+	block, _ := pem.Decode(authInfo.ClientCertificateData)
+	if block == nil {
+		return false
+	}
+	clientCert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false
+	}
+
+	if time.Now().After(clientCert.NotAfter) {
+		return false
+	}
+
+	return clientCert.CheckSignatureFrom(caCert) == nil
+}